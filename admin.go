@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// adminServer builds the daemon's HTTP admin API: GET /accounts, GET
+// /accounts/{email}/cookies, POST /accounts/{email}/refresh, GET /healthz,
+// and GET /metrics.
+func adminServer(d *Daemon) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/metrics", handleMetrics(d))
+	mux.HandleFunc("/accounts", handleAccounts(d))
+	mux.HandleFunc("/accounts/", handleAccountByEmail(d))
+	return mux
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+func handleMetrics(d *Daemon) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := d.Runner.Metrics.WriteText(w); err != nil {
+			log.Printf("Error writing metrics: %v", err)
+		}
+	}
+}
+
+func handleAccounts(d *Daemon) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, d.Status())
+	}
+}
+
+// handleAccountByEmail serves GET /accounts/{email}/cookies and POST
+// /accounts/{email}/refresh.
+func handleAccountByEmail(d *Daemon) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/accounts/")
+		parts := strings.SplitN(path, "/", 2)
+		if len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		email, action := parts[0], parts[1]
+
+		switch {
+		case action == "cookies" && r.Method == http.MethodGet:
+			data, err := d.Storage.GetCookies(email)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(data)
+
+		case action == "refresh" && r.Method == http.MethodPost:
+			if err := d.RefreshNow(r.Context(), email); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error encoding JSON response: %v", err)
+	}
+}
@@ -4,15 +4,25 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"math/rand"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
+	"github.com/itmanvn/amazon-login/captcha"
+	"github.com/itmanvn/amazon-login/config"
+	"github.com/itmanvn/amazon-login/cookiestore"
+	"github.com/itmanvn/amazon-login/metrics"
+	"github.com/itmanvn/amazon-login/otp"
+	"github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
 )
 
 type Account struct {
@@ -70,10 +80,39 @@ func loadProxies(filePath string) ([]Proxy, error) {
 	return proxies, scanner.Err()
 }
 
+// Load the daemon's watch-list: one "email" or "email:interval" per line.
+// Entries without an explicit interval (or with one that fails to parse)
+// use defaultInterval.
+func loadWatchList(filePath string, defaultInterval time.Duration) ([]WatchEntry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []WatchEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		interval := defaultInterval
+		if len(parts) == 2 {
+			if d, err := time.ParseDuration(parts[1]); err == nil {
+				interval = d
+			}
+		}
+		entries = append(entries, WatchEntry{Email: parts[0], Interval: interval})
+	}
+	return entries, scanner.Err()
+}
+
 // Load account-to-proxy mapping
-func loadMapping(filePath string) (map[string]Proxy, error) {
-	data, err := ioutil.ReadFile(filePath)
-	if os.IsNotExist(err) {
+func loadMapping(store storage.Driver) (map[string]Proxy, error) {
+	data, err := store.GetMapping()
+	if err == storage.ErrNotFound {
 		return make(map[string]Proxy), nil
 	}
 	if err != nil {
@@ -91,7 +130,7 @@ func loadMapping(filePath string) (map[string]Proxy, error) {
 }
 
 // Save account-to-proxy mapping
-func saveMapping(filePath string, mapping map[string]Proxy) error {
+func saveMapping(store storage.Driver, mapping map[string]Proxy) error {
 	var mappings []AccountProxyMapping
 	for email, proxy := range mapping {
 		mappings = append(mappings, AccountProxyMapping{Email: email, Proxy: proxy})
@@ -100,193 +139,210 @@ func saveMapping(filePath string, mapping map[string]Proxy) error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(filePath, data, 0644)
+	return store.PutMapping(data)
 }
 
-// Load cookies
-func loadCookies(email string) ([]*chromedp.Cookie, error) {
-	cookieFile := fmt.Sprintf("cookies_%s.json", strings.Replace(email, "@", "_", -1))
-	data, err := ioutil.ReadFile(cookieFile)
-	if err != nil {
-		return nil, err
+// performLogin drives the sign-in form for account, solving whatever
+// captcha or OTP challenge Amazon interposes (if solver/otpProvider are
+// configured for it), and sends the resulting session cookies on
+// cookiesChan. Failures before the sign-in page finishes loading are
+// reported as ErrProxyBlocked, since that's usually a rate-limited or
+// blocked proxy rather than a bad account.
+func performLogin(ctx context.Context, account Account, cfg *config.Config, solver captcha.Solver, otpProvider otp.Provider, cookiesChan chan<- []*network.CookieParam) error {
+	sel := cfg.Active.Selectors
+
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(cfg.Active.SignInURL),
+		chromedp.Sleep(cfg.StepSleep),
+		chromedp.WaitVisible(sel.Email, chromedp.ByID),
+	); err != nil {
+		return fmt.Errorf("%w: %v", ErrProxyBlocked, err)
 	}
-	var cookies []*chromedp.Cookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
-		return nil, err
+
+	if err := chromedp.Run(ctx,
+		chromedp.SendKeys(sel.Email, account.Email, chromedp.ByID),
+		chromedp.Click(sel.Continue, chromedp.ByID),
+		chromedp.Sleep(cfg.StepSleep),
+		chromedp.WaitVisible(sel.Password, chromedp.ByID),
+		chromedp.SendKeys(sel.Password, account.Password, chromedp.ByID),
+		chromedp.Click(sel.Submit, chromedp.ByID),
+		chromedp.Sleep(cfg.StepSleep),
+	); err != nil {
+		return err
 	}
-	return cookies, nil
-}
 
-// Select random account and get its proxy
-func selectRandomAccountAndProxy(accounts []Account, proxies []Proxy, mapping map[string]Proxy) (Account, Proxy) {
-	rand.Seed(time.Now().UnixNano())
-	account := accounts[rand.Intn(len(accounts))]
-	proxy, exists := mapping[account.Email]
-	if !exists {
-		proxy = proxies[rand.Intn(len(proxies))]
-		mapping[account.Email] = proxy
+	if err := handleChallenge(ctx, cfg, account.Email, solver, otpProvider); err != nil {
+		return err
 	}
-	return account, proxy
-}
 
-// Perform login
-func performLogin(ctx context.Context, account Account, cookiesChan chan<- []*chromedp.Cookie) error {
 	return chromedp.Run(ctx,
-		chromedp.Navigate("https://www.amazon.com/ap/signin"),
-		chromedp.Sleep(2*time.Second),
-		chromedp.WaitVisible(`#ap_email`, chromedp.ByID),
-		chromedp.SendKeys(`#ap_email`, account.Email, chromedp.ByID),
-		chromedp.Click(`#continue`, chromedp.ByID),
-		chromedp.Sleep(1*time.Second),
-		chromedp.WaitVisible(`#ap_password`, chromedp.ByID),
-		chromedp.SendKeys(`#ap_password`, account.Password, chromedp.ByID),
-		chromedp.Click(`#signInSubmit`, chromedp.ByID),
-		chromedp.Sleep(2*time.Second),
-		chromedp.WaitVisible(`#nav-link-accountList`, chromedp.ByID),
+		chromedp.WaitVisible(sel.AccountList, chromedp.ByID),
 		chromedp.ActionFunc(func(ctx context.Context) error {
-			cookies, err := chromedp.Cookies().Do(ctx)
+			cookies, err := network.GetCookies().Do(ctx)
 			if err != nil {
 				return err
 			}
-			cookiesChan <- cookies
+			params := make([]*network.CookieParam, len(cookies))
+			for i, c := range cookies {
+				params[i] = &network.CookieParam{
+					Name:     c.Name,
+					Value:    c.Value,
+					Domain:   c.Domain,
+					Path:     c.Path,
+					Secure:   c.Secure,
+					HTTPOnly: c.HTTPOnly,
+					SameSite: c.SameSite,
+					Priority: c.Priority,
+					Expires:  c.Expires,
+				}
+			}
+			cookiesChan <- params
 			return nil
 		}),
 	)
 }
 
+// configFlagValue scans args for -c/--c (or -c=.../--c=...) ahead of the
+// full flag.FlagSet being built, since the config file itself must be
+// loaded before Config's fields can be registered as flags.
+func configFlagValue(args []string) string {
+	for i, arg := range args {
+		switch {
+		case arg == "-c" || arg == "--c":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		case strings.HasPrefix(arg, "-c="):
+			return strings.TrimPrefix(arg, "-c=")
+		case strings.HasPrefix(arg, "--c="):
+			return strings.TrimPrefix(arg, "--c=")
+		}
+	}
+	return ""
+}
+
 func main() {
-	// Load accounts, proxies, and mapping
-	accounts, err := loadAccounts("accounts.txt")
+	args := os.Args[1:]
+	configPath := configFlagValue(args)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Error loading config: %v", err)
+	}
+
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	fs.StringVar(&configPath, "c", configPath, "path to config file (JSON or YAML)")
+	config.BindFlags(fs, cfg)
+	fs.Parse(args)
+
+	// -profile may have changed cfg.Profile after Load resolved cfg.Active.
+	profile, ok := cfg.Profiles[cfg.Profile]
+	if !ok {
+		log.Fatalf("Error loading config: unknown profile %q", cfg.Profile)
+	}
+	cfg.Active = profile
+
+	store, err := factory.Create(cfg.Storage.Driver, cfg.Storage.Parameters)
+	if err != nil {
+		log.Fatalf("Error creating storage driver: %v", err)
+	}
+
+	// Load accounts, proxies, mapping, and proxy health
+	accounts, err := loadAccounts(cfg.AccountsFile)
 	if err != nil {
 		log.Fatalf("Error loading accounts: %v", err)
 	}
-	proxies, err := loadProxies("proxies.txt")
+	proxies, err := loadProxies(cfg.ProxiesFile)
 	if err != nil {
 		log.Fatalf("Error loading proxies: %v", err)
 	}
-	mapping, err := loadMapping("account_proxy_mapping.json")
+	mapping, err := loadMapping(store)
 	if err != nil {
 		log.Fatalf("Error loading mapping: %v", err)
 	}
+	health, err := LoadProxyHealth(cfg.ProxyHealthFile)
+	if err != nil {
+		log.Fatalf("Error loading proxy health: %v", err)
+	}
 
-	// Select random account and its proxy
-	account, proxy := selectRandomAccountAndProxy(accounts, proxies, mapping)
-	log.Printf("Using account: %s, proxy: %s:%s", account.Email, proxy.Server, proxy.Port)
-
-	// Create chromedp context
-	ctx, cancel := chromedp.NewContext(context.Background(), chromedp.WithLogf(log.Printf))
-	defer cancel()
+	runner, err := NewRunner(cfg, store, proxies, mapping, health, metrics.NewCounters())
+	if err != nil {
+		log.Fatalf("Error creating runner: %v", err)
+	}
 
-	// Configure headless Chrome with proxy
-	ctx, cancel = chromedp.NewExecAllocator(ctx,
-		chromedp.NoFirstRun,
-		chromedp.NoDefaultBrowserCheck,
-		chromedp.Headless,
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
-		chromedp.WindowSize(1920, 1080),
-		chromedp.ProxyServer(fmt.Sprintf("http://%s:%s", proxy.Server, proxy.Port)),
-	)
-	defer cancel()
+	if cfg.DaemonEnabled {
+		runDaemon(cfg, runner, store, accounts)
+		return
+	}
 
-	// Channel for cookies
-	cookiesChan := make(chan []*chromedp.Cookie, 1)
+	runOnce(cfg, runner, store, accounts, health)
+}
 
-	// Check for proxy failure
-	proxyFailed := false
-	err = chromedp.Run(ctx,
-		chromedp.Navigate("https://www.amazon.com"),
-		chromedp.Sleep(2*time.Second),
-	)
-	if err != nil {
-		log.Printf("Proxy %s:%s failed: %v", proxy.Server, proxy.Port, err)
-		proxyFailed = true
-	}
+// runOnce logs in to every account once, saves any resulting cookies and
+// the account-to-proxy mapping, and exits.
+func runOnce(cfg *config.Config, runner *Runner, store storage.Driver, accounts []Account, health *ProxyHealth) {
+	ctx := context.Background()
+	results := runner.Run(ctx, accounts)
 
-	// Replace proxy if failed
-	if proxyFailed {
-		log.Println("Selecting new proxy")
-		newProxy := proxies[rand.Intn(len(proxies))]
-		for newProxy.Server == proxy.Server && newProxy.Port == proxy.Port {
-			newProxy = proxies[rand.Intn(len(proxies))]
+	succeeded, failed := 0, 0
+	for res := range results {
+		if res.Err != nil {
+			log.Printf("Login failed for %s via %s:%s: %v", res.Account.Email, res.Proxy.Server, res.Proxy.Port, res.Err)
+			failed++
+			continue
 		}
-		proxy = newProxy
-		mapping[account.Email] = proxy
-		log.Printf("New proxy for %s: %s:%s", account.Email, proxy.Server, proxy.Port)
-		// Reconfigure context with new proxy
-		ctx, cancel = chromedp.NewContext(context.Background(), chromedp.WithLogf(log.Printf))
-		defer cancel()
-		ctx, cancel = chromedp.NewExecAllocator(ctx,
-			chromedp.NoFirstRun,
-			chromedp.NoDefaultBrowserCheck,
-			chromedp.Headless,
-			chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
-			chromedp.WindowSize(1920, 1080),
-			chromedp.ProxyServer(fmt.Sprintf("http://%s:%s", proxy.Server, proxy.Port)),
-		)
-		defer cancel()
-	}
 
-	// Check for existing cookies
-	cookieFile := fmt.Sprintf("cookies_%s.json", strings.Replace(account.Email, "@", "_", -1))
-	if cookies, err := loadCookies(account.Email); err == nil {
-		log.Printf("Cookies found for %s, attempting to reuse", account.Email)
-		err = chromedp.Run(ctx,
-			chromedp.ActionFunc(func(ctx context.Context) error {
-				for _, cookie := range cookies {
-					err := chromedp.SetCookie(cookie.Name, cookie.Value, cookie.Path, cookie.Domain, cookie.Expires, cookie.Secure, cookie.HttpOnly).Do(ctx)
-					if err != nil {
-						return err
-					}
-				}
-				return nil
-			}),
-			chromedp.Navigate("https://www.amazon.com"),
-			chromedp.Sleep(2*time.Second),
-			chromedp.ActionFunc(func(ctx context.Context) error {
-				var exists bool
-				err := chromedp.Evaluate(`document.querySelector("#nav-link-accountList") !== null`, &exists).Do(ctx)
-				if err == nil && exists {
-					log.Printf("Cookies valid for %s", account.Email)
-					cookiesChan <- cookies
-					return nil
-				}
-				log.Printf("Cookies invalid for %s, performing fresh login", account.Email)
-				return performLogin(ctx, account, cookiesChan)
-			}),
-		)
+		data, err := cookiestore.EncodeJSON(res.Cookies)
 		if err != nil {
-			log.Printf("Error using cookies: %v", err)
-			return
+			log.Printf("Error encoding cookies for %s: %v", res.Account.Email, err)
+			failed++
+			continue
 		}
-	} else {
-		log.Printf("No cookies found for %s, performing fresh login", account.Email)
-		err = performLogin(ctx, account, cookiesChan)
-		if err != nil {
-			log.Printf("Login failed for %s: %v", account.Email, err)
-			var html string
-			chromedp.OuterHTML("html", &html).Do(ctx)
-			log.Println("Page source:", html)
-			return
+		if err := store.PutCookies(res.Account.Email, data); err != nil {
+			log.Printf("Error saving cookies for %s: %v", res.Account.Email, err)
+			failed++
+			continue
 		}
+		log.Printf("Success for %s in %s! Cookies saved via %s:%s", res.Account.Email, res.Elapsed, res.Proxy.Server, res.Proxy.Port)
+		succeeded++
 	}
 
-	// Save cookies
-	cookies := <-cookiesChan
-	cookieData, err := json.Marshal(cookies)
-	if err != nil {
-		log.Printf("Error marshaling cookies: %v", err)
-		return
+	if err := saveMapping(store, runner.Mapping); err != nil {
+		log.Printf("Error saving mapping: %v", err)
 	}
-	if err := ioutil.WriteFile(cookieFile, cookieData, 0644); err != nil {
-		log.Printf("Error saving cookies: %v", err)
-		return
+	if err := health.Save(cfg.ProxyHealthFile); err != nil {
+		log.Printf("Error saving proxy health: %v", err)
 	}
 
-	// Save updated mapping
-	if err := saveMapping("account_proxy_mapping.json", mapping); err != nil {
-		log.Printf("Error saving mapping: %v", err)
-		return
+	log.Printf("Done: %d succeeded, %d failed", succeeded, failed)
+}
+
+// runDaemon keeps every watch-listed account's session warm, serving the
+// admin API until interrupted.
+func runDaemon(cfg *config.Config, runner *Runner, store storage.Driver, accounts []Account) {
+	watchList, err := loadWatchList(cfg.WatchFile, cfg.RefreshInterval)
+	if err != nil {
+		log.Fatalf("Error loading watch list: %v", err)
 	}
 
-	log.Printf("Success for %s! Cookies saved to %s, mapping updated", account.Email, cookieFile)
+	daemon := NewDaemon(runner, store, accounts)
+
+	server := &http.Server{Addr: cfg.AdminAddr, Handler: adminServer(daemon)}
+	go func() {
+		log.Printf("Admin API listening on %s", cfg.AdminAddr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Admin API error: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	daemon.Watch(ctx, watchList)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down admin API: %v", err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,10 @@
+// Package captcha defines the interface used to solve Amazon's sign-in
+// captcha challenge, plus a stub HTTP-based solver implementation.
+package captcha
+
+import "context"
+
+// Solver solves a captcha image and returns the text it depicts.
+type Solver interface {
+	Solve(ctx context.Context, imagePNG []byte) (string, error)
+}
@@ -0,0 +1,42 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// postForm POSTs form-encoded values to rawURL and decodes a JSON response
+// into out.
+func postForm(ctx context.Context, client *http.Client, rawURL string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return do(client, req, out)
+}
+
+// getJSON GETs rawURL and decodes a JSON response into out.
+func getJSON(ctx context.Context, client *http.Client, rawURL string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	return do(client, req, out)
+}
+
+func do(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha: %s returned %s", req.URL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
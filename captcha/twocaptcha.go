@@ -0,0 +1,99 @@
+package captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TwoCaptchaSolver solves captchas via the 2captcha HTTP API. It also works
+// against anti-captcha, which exposes a compatible endpoint shape.
+type TwoCaptchaSolver struct {
+	APIKey     string
+	BaseURL    string // defaults to https://2captcha.com
+	HTTPClient *http.Client
+	PollEvery  time.Duration // defaults to 5s
+	Timeout    time.Duration // defaults to 2m
+}
+
+func (s *TwoCaptchaSolver) baseURL() string {
+	if s.BaseURL != "" {
+		return s.BaseURL
+	}
+	return "https://2captcha.com"
+}
+
+func (s *TwoCaptchaSolver) httpClient() *http.Client {
+	if s.HTTPClient != nil {
+		return s.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Solve submits imagePNG for solving and polls until a solution is ready.
+func (s *TwoCaptchaSolver) Solve(ctx context.Context, imagePNG []byte) (string, error) {
+	id, err := s.submit(ctx, imagePNG)
+	if err != nil {
+		return "", err
+	}
+	return s.poll(ctx, id)
+}
+
+func (s *TwoCaptchaSolver) submit(ctx context.Context, imagePNG []byte) (string, error) {
+	form := url.Values{
+		"key":    {s.APIKey},
+		"method": {"base64"},
+		"body":   {base64.StdEncoding.EncodeToString(imagePNG)},
+		"json":   {"1"},
+	}
+	var result struct {
+		Status  int    `json:"status"`
+		Request string `json:"request"`
+	}
+	if err := postForm(ctx, s.httpClient(), s.baseURL()+"/in.php", form, &result); err != nil {
+		return "", err
+	}
+	if result.Status != 1 {
+		return "", fmt.Errorf("captcha: submit failed: %s", result.Request)
+	}
+	return result.Request, nil
+}
+
+func (s *TwoCaptchaSolver) poll(ctx context.Context, id string) (string, error) {
+	pollEvery := s.PollEvery
+	if pollEvery == 0 {
+		pollEvery = 5 * time.Second
+	}
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		var result struct {
+			Status  int    `json:"status"`
+			Request string `json:"request"`
+		}
+		q := url.Values{"key": {s.APIKey}, "action": {"get"}, "id": {id}, "json": {"1"}}
+		if err := getJSON(ctx, s.httpClient(), s.baseURL()+"/res.php?"+q.Encode(), &result); err != nil {
+			return "", err
+		}
+		if result.Status == 1 {
+			return result.Request, nil
+		}
+		if result.Request != "" && !strings.Contains(result.Request, "CAPCHA_NOT_READY") {
+			return "", fmt.Errorf("captcha: solve failed: %s", result.Request)
+		}
+		select {
+		case <-time.After(pollEvery):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return "", fmt.Errorf("captcha: timed out waiting for solution")
+}
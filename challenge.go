@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"github.com/itmanvn/amazon-login/captcha"
+	"github.com/itmanvn/amazon-login/config"
+	"github.com/itmanvn/amazon-login/otp"
+)
+
+// challengeKind identifies which, if any, of the selectors in a
+// config.Selectors matched the current page.
+type challengeKind int
+
+const (
+	challengeNone challengeKind = iota
+	challengeCaptcha
+	challengeOTP
+	challengeImportantMessage
+	challengeLocked
+)
+
+// maxChallengeRounds bounds how many times handleChallenge will solve a
+// captcha or OTP and re-check the page before giving up, so a solver that
+// keeps getting it wrong can't loop forever.
+const maxChallengeRounds = 3
+
+// detectChallenge checks the live DOM for whichever of the account-locked,
+// important-message, captcha, or OTP selectors appears first, in that order
+// of precedence (an account-locked page takes priority over a captcha that
+// happens to also be present).
+func detectChallenge(ctx context.Context, sel config.Selectors) (challengeKind, error) {
+	script := fmt.Sprintf(`(function() {
+		if (document.querySelector(%s)) return "locked";
+		if (document.querySelector(%s)) return "important";
+		if (document.querySelector(%s)) return "captcha";
+		if (document.querySelector(%s)) return "otp";
+		return "";
+	})()`, jsString(sel.AccountLocked), jsString(sel.ImportantMessage), jsString(sel.Captcha), jsString(sel.OTP))
+
+	var which string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(script, &which)); err != nil {
+		return challengeNone, err
+	}
+
+	switch which {
+	case "locked":
+		return challengeLocked, nil
+	case "important":
+		return challengeImportantMessage, nil
+	case "captcha":
+		return challengeCaptcha, nil
+	case "otp":
+		return challengeOTP, nil
+	default:
+		return challengeNone, nil
+	}
+}
+
+// handleChallenge detects and, where possible, clears any captcha or OTP
+// challenge standing between the submit click and the account page. It
+// returns nil once neither challenge is present, or one of the typed
+// challenge errors if it can't proceed (no solver/provider configured, or
+// the account is locked).
+func handleChallenge(ctx context.Context, cfg *config.Config, email string, solver captcha.Solver, otpProvider otp.Provider) error {
+	sel := cfg.Active.Selectors
+
+	for round := 0; round <= maxChallengeRounds; round++ {
+		kind, err := detectChallenge(ctx, sel)
+		if err != nil {
+			return err
+		}
+		if round == maxChallengeRounds && (kind == challengeCaptcha || kind == challengeOTP) {
+			break
+		}
+
+		switch kind {
+		case challengeNone:
+			return nil
+		case challengeLocked, challengeImportantMessage:
+			return ErrLocked
+		case challengeCaptcha:
+			if solver == nil {
+				return ErrCaptcha
+			}
+			if err := solveCaptcha(ctx, sel, solver); err != nil {
+				return err
+			}
+		case challengeOTP:
+			if otpProvider == nil {
+				return ErrOTP
+			}
+			if err := solveOTP(ctx, sel, otpProvider, email); err != nil {
+				return err
+			}
+		}
+
+		if err := chromedp.Run(ctx, chromedp.Sleep(cfg.StepSleep)); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("amazon-login: gave up after %d challenge rounds", maxChallengeRounds)
+}
+
+func solveCaptcha(ctx context.Context, sel config.Selectors, solver captcha.Solver) error {
+	var imagePNG []byte
+	if err := chromedp.Run(ctx, chromedp.Screenshot(sel.Captcha, &imagePNG, chromedp.ByID)); err != nil {
+		return err
+	}
+
+	guess, err := solver.Solve(ctx, imagePNG)
+	if err != nil {
+		return err
+	}
+
+	return chromedp.Run(ctx,
+		chromedp.SendKeys(sel.CaptchaGuess, guess, chromedp.ByID),
+		chromedp.Click(sel.CaptchaSubmit, chromedp.ByID),
+	)
+}
+
+func solveOTP(ctx context.Context, sel config.Selectors, provider otp.Provider, email string) error {
+	code, err := provider.Code(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return chromedp.Run(ctx,
+		chromedp.SendKeys(sel.OTP, code, chromedp.ByID),
+		chromedp.Click(sel.OTPSubmit, chromedp.ByID),
+	)
+}
+
+// jsString renders s as a double-quoted JavaScript string literal for
+// splicing into an Evaluate script.
+func jsString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// bindEnv walks cfg's fields and, for each with a non-empty `env` tag whose
+// variable is set, overrides the field's value.
+func bindEnv(cfg *Config) {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		envName := field.Tag.Get("env")
+		if envName == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(envName)
+		if !ok {
+			continue
+		}
+		setField(v.Field(i), raw)
+	}
+}
+
+// BindFlags registers a flag for every Config field with a `flag` tag,
+// defaulting to cfg's current value (so file and env overrides already
+// applied by Load take effect unless overridden on the command line).
+// Callers must call fs.Parse after BindFlags for the bound flags to take
+// effect.
+func BindFlags(fs *flag.FlagSet, cfg *Config) {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		flagName := field.Tag.Get("flag")
+		if flagName == "" {
+			continue
+		}
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.String:
+			fs.StringVar(fv.Addr().Interface().(*string), flagName, fv.String(), field.Name)
+		case reflect.Bool:
+			fs.BoolVar(fv.Addr().Interface().(*bool), flagName, fv.Bool(), field.Name)
+		case reflect.Int, reflect.Int64:
+			if fv.Type() == reflect.TypeOf(time.Duration(0)) {
+				fs.DurationVar(fv.Addr().Interface().(*time.Duration), flagName, time.Duration(fv.Int()), field.Name)
+			} else {
+				fs.IntVar(fv.Addr().Interface().(*int), flagName, int(fv.Int()), field.Name)
+			}
+		}
+	}
+}
+
+// setField parses raw into field's type and sets it. Unsupported kinds and
+// unparsable values are silently left unchanged, matching the tolerant
+// override behavior of the rest of the loader.
+func setField(field reflect.Value, raw string) {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			field.SetBool(b)
+		}
+	case reflect.Int, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			if d, err := time.ParseDuration(raw); err == nil {
+				field.SetInt(int64(d))
+			}
+			return
+		}
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			field.SetInt(n)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Package config loads runner configuration from a JSON or YAML file, with
+// per-field command-line flag and environment variable overrides, and
+// supports multiple named marketplace profiles (e.g. US, UK, JP) in a
+// single config file.
+package config
+
+import "time"
+
+// Selectors holds the CSS/ID selectors used to drive the Amazon sign-in
+// flow. Different marketplaces (amazon.com vs amazon.co.jp, etc.) can serve
+// different markup, so these are profile-scoped rather than global.
+type Selectors struct {
+	Email            string `json:"email_selector" yaml:"email_selector" cfg:"email_selector"`
+	Continue         string `json:"continue_selector" yaml:"continue_selector" cfg:"continue_selector"`
+	Password         string `json:"password_selector" yaml:"password_selector" cfg:"password_selector"`
+	Submit           string `json:"submit_selector" yaml:"submit_selector" cfg:"submit_selector"`
+	AccountList      string `json:"account_list_selector" yaml:"account_list_selector" cfg:"account_list_selector"`
+	Captcha          string `json:"captcha_selector" yaml:"captcha_selector" cfg:"captcha_selector"`
+	CaptchaGuess     string `json:"captcha_guess_selector" yaml:"captcha_guess_selector" cfg:"captcha_guess_selector"`
+	CaptchaSubmit    string `json:"captcha_submit_selector" yaml:"captcha_submit_selector" cfg:"captcha_submit_selector"`
+	OTP              string `json:"otp_selector" yaml:"otp_selector" cfg:"otp_selector"`
+	OTPSubmit        string `json:"otp_submit_selector" yaml:"otp_submit_selector" cfg:"otp_submit_selector"`
+	ImportantMessage string `json:"important_message_selector" yaml:"important_message_selector" cfg:"important_message_selector"`
+	AccountLocked    string `json:"account_locked_selector" yaml:"account_locked_selector" cfg:"account_locked_selector"`
+}
+
+// Profile bundles the URLs and selectors needed to run the login flow
+// against one Amazon marketplace.
+type Profile struct {
+	SignInURL string    `json:"sign_in_url" yaml:"sign_in_url" cfg:"sign_in_url"`
+	HomeURL   string    `json:"home_url" yaml:"home_url" cfg:"home_url"`
+	Selectors Selectors `json:"selectors" yaml:"selectors" cfg:"selectors"`
+}
+
+// StorageConfig selects and configures the storage.Driver used to persist
+// cookies and the account-to-proxy mapping. Driver is a name registered by
+// a storage subpackage's init() (see storage/factory); Parameters is passed
+// through to that driver's factory verbatim.
+type StorageConfig struct {
+	Driver     string                 `json:"driver" yaml:"driver" cfg:"driver"`
+	Parameters map[string]interface{} `json:"parameters" yaml:"parameters" cfg:"parameters"`
+}
+
+// CaptchaConfig selects and configures the captcha.Solver used when a
+// login hits Amazon's captcha challenge. An empty Provider disables
+// automatic solving; the challenge then surfaces as ErrCaptcha.
+type CaptchaConfig struct {
+	Provider string `json:"provider" yaml:"provider" cfg:"provider"`
+	APIKey   string `json:"api_key" yaml:"api_key" cfg:"api_key"`
+}
+
+// OTPConfig selects and configures the otp.Provider used when a login hits
+// Amazon's MFA/OTP challenge. An empty Provider disables automatic
+// completion; the challenge then surfaces as ErrOTP.
+type OTPConfig struct {
+	Provider    string `json:"provider" yaml:"provider" cfg:"provider"`
+	Secret      string `json:"secret" yaml:"secret" cfg:"secret"`
+	CallbackURL string `json:"callback_url" yaml:"callback_url" cfg:"callback_url"`
+}
+
+// defaultProfile returns the built-in amazon.com profile, used when a
+// config file doesn't define or select one.
+func defaultProfile() Profile {
+	return Profile{
+		SignInURL: "https://www.amazon.com/ap/signin",
+		HomeURL:   "https://www.amazon.com",
+		Selectors: Selectors{
+			Email:            "#ap_email",
+			Continue:         "#continue",
+			Password:         "#ap_password",
+			Submit:           "#signInSubmit",
+			AccountList:      "#nav-link-accountList",
+			Captcha:          "#auth-captcha-image",
+			CaptchaGuess:     "#auth-captcha-guess",
+			CaptchaSubmit:    "#auth-signin-button",
+			OTP:              "#auth-mfa-otpcode",
+			OTPSubmit:        "#auth-signin-button",
+			ImportantMessage: "#auth-warning-message-box",
+			AccountLocked:    "#auth-error-message-box",
+		},
+	}
+}
+
+// Config is the full set of tunables for a run, bindable from a config
+// file, environment variables, and command-line flags. Struct tags name the
+// binding for each source: `flag` for -flag name, `env` for the
+// environment variable, `cfg` for the file key. Files are decoded through
+// the matching `json`/`yaml` tags; no code reads the `cfg` tag itself.
+type Config struct {
+	AccountsFile    string        `json:"accounts_file" yaml:"accounts_file" flag:"accounts-file" env:"AMZLOGIN_ACCOUNTS_FILE" cfg:"accounts_file"`
+	ProxiesFile     string        `json:"proxies_file" yaml:"proxies_file" flag:"proxies-file" env:"AMZLOGIN_PROXIES_FILE" cfg:"proxies_file"`
+	MappingFile     string        `json:"mapping_file" yaml:"mapping_file" flag:"mapping-file" env:"AMZLOGIN_MAPPING_FILE" cfg:"mapping_file"`
+	ProxyHealthFile string        `json:"proxy_health_file" yaml:"proxy_health_file" flag:"proxy-health-file" env:"AMZLOGIN_PROXY_HEALTH_FILE" cfg:"proxy_health_file"`
+	Headless        bool          `json:"headless" yaml:"headless" flag:"headless" env:"AMZLOGIN_HEADLESS" cfg:"headless"`
+	UserAgent       string        `json:"user_agent" yaml:"user_agent" flag:"user-agent" env:"AMZLOGIN_USER_AGENT" cfg:"user_agent"`
+	WindowWidth     int           `json:"window_width" yaml:"window_width" flag:"window-width" env:"AMZLOGIN_WINDOW_WIDTH" cfg:"window_width"`
+	WindowHeight    int           `json:"window_height" yaml:"window_height" flag:"window-height" env:"AMZLOGIN_WINDOW_HEIGHT" cfg:"window_height"`
+	StepSleep       time.Duration `json:"step_sleep" yaml:"step_sleep" flag:"step-sleep" env:"AMZLOGIN_STEP_SLEEP" cfg:"step_sleep"`
+	RetryCount      int           `json:"retry_count" yaml:"retry_count" flag:"retry-count" env:"AMZLOGIN_RETRY_COUNT" cfg:"retry_count"`
+	RetryInterval   time.Duration `json:"retry_interval" yaml:"retry_interval" flag:"retry-interval" env:"AMZLOGIN_RETRY_INTERVAL" cfg:"retry_interval"`
+	Concurrency     int           `json:"concurrency" yaml:"concurrency" flag:"concurrency" env:"AMZLOGIN_CONCURRENCY" cfg:"concurrency"`
+
+	// Daemon mode keeps watched accounts' sessions warm instead of running
+	// once and exiting; see DaemonEnabled.
+	DaemonEnabled   bool          `json:"daemon_enabled" yaml:"daemon_enabled" flag:"daemon" env:"AMZLOGIN_DAEMON" cfg:"daemon_enabled"`
+	AdminAddr       string        `json:"admin_addr" yaml:"admin_addr" flag:"admin-addr" env:"AMZLOGIN_ADMIN_ADDR" cfg:"admin_addr"`
+	WatchFile       string        `json:"watch_file" yaml:"watch_file" flag:"watch-file" env:"AMZLOGIN_WATCH_FILE" cfg:"watch_file"`
+	RefreshInterval time.Duration `json:"refresh_interval" yaml:"refresh_interval" flag:"refresh-interval" env:"AMZLOGIN_REFRESH_INTERVAL" cfg:"refresh_interval"`
+
+	// Profile selects which entry of Profiles to run with; it is itself
+	// file/env/flag-bindable, but the Profiles map is file-only.
+	Profile string `json:"profile" yaml:"profile" flag:"profile" env:"AMZLOGIN_PROFILE" cfg:"profile"`
+
+	// Active is the resolved Profile for Profile, filled in by Load.
+	Active Profile `json:"-" yaml:"-" cfg:"-"`
+
+	Profiles map[string]Profile `json:"profiles" yaml:"profiles" cfg:"-"`
+
+	Storage StorageConfig `json:"storage" yaml:"storage" cfg:"-"`
+	Captcha CaptchaConfig `json:"captcha" yaml:"captcha" cfg:"-"`
+	OTP     OTPConfig     `json:"otp" yaml:"otp" cfg:"-"`
+}
+
+// Default returns a Config with the same hard-coded values the tool used
+// before it was made configurable.
+func Default() *Config {
+	return &Config{
+		AccountsFile:    "accounts.txt",
+		ProxiesFile:     "proxies.txt",
+		MappingFile:     "account_proxy_mapping.json",
+		ProxyHealthFile: "proxy_health.json",
+		Headless:        true,
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+		WindowWidth:     1920,
+		WindowHeight:    1080,
+		StepSleep:       2 * time.Second,
+		RetryCount:      3,
+		RetryInterval:   2 * time.Second,
+		Concurrency:     4,
+		DaemonEnabled:   false,
+		AdminAddr:       ":8090",
+		WatchFile:       "watch.txt",
+		RefreshInterval: 30 * time.Minute,
+		Profile:         "us",
+		Active:          defaultProfile(),
+		Profiles:        map[string]Profile{"us": defaultProfile()},
+		Storage: StorageConfig{
+			Driver:     "filesystem",
+			Parameters: map[string]interface{}{"rootdirectory": "."},
+		},
+	}
+}
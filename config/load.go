@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads configPath (JSON or YAML, picked by extension) if non-empty,
+// overlays it onto Default(), applies environment variable overrides, then
+// resolves the selected profile. Command-line flags are bound separately
+// via BindFlags so callers can parse them alongside Load's result.
+func Load(configPath string) (*Config, error) {
+	cfg := Default()
+
+	if configPath != "" {
+		data, err := ioutil.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("config: reading %s: %w", configPath, err)
+		}
+		if err := unmarshalInto(configPath, data, cfg); err != nil {
+			return nil, fmt.Errorf("config: parsing %s: %w", configPath, err)
+		}
+	}
+
+	bindEnv(cfg)
+
+	profile, ok := cfg.Profiles[cfg.Profile]
+	if !ok {
+		return nil, fmt.Errorf("config: unknown profile %q", cfg.Profile)
+	}
+	cfg.Active = profile
+
+	return cfg, nil
+}
+
+// unmarshalInto decodes data into cfg, picking JSON or YAML based on path's
+// extension.
+func unmarshalInto(path string, data []byte, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yaml.Unmarshal(data, cfg)
+	default:
+		return json.Unmarshal(data, cfg)
+	}
+}
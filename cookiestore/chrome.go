@@ -0,0 +1,90 @@
+package cookiestore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// chromeEpoch is the WebKit/Chrome epoch (1601-01-01) that cookie
+// expiration timestamps are measured from, in microseconds.
+var chromeEpoch = time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ImportChromeSQLite reads cookies from a Chrome/Chromium "Cookies" SQLite
+// file of the kind produced by browser-data extraction tools (e.g.
+// HackBrowserData), which store the `value` column already decrypted.
+func ImportChromeSQLite(path string) ([]*network.CookieParam, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT host_key, name, value, path, expires_utc, is_secure, is_httponly, samesite, priority FROM cookies`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cookies []*network.CookieParam
+	for rows.Next() {
+		var (
+			host, name, value, path        string
+			expiresUTC                     int64
+			isSecure, isHTTPOnly, sameSite int
+			priority                       int
+		)
+		if err := rows.Scan(&host, &name, &value, &path, &expiresUTC, &isSecure, &isHTTPOnly, &sameSite, &priority); err != nil {
+			return nil, err
+		}
+		cookies = append(cookies, &network.CookieParam{
+			Name:     name,
+			Value:    value,
+			Domain:   host,
+			Path:     path,
+			Secure:   isSecure != 0,
+			HTTPOnly: isHTTPOnly != 0,
+			SameSite: chromeSameSite(sameSite),
+			Priority: chromePriority(priority),
+			Expires:  chromeTimeToUnix(expiresUTC),
+		})
+	}
+	return cookies, rows.Err()
+}
+
+// chromeTimeToUnix converts a Chrome cookie timestamp (microseconds since
+// 1601-01-01) to a CDP network.TimeSinceEpoch (seconds since the Unix
+// epoch). A zero input, meaning "session cookie", maps to zero.
+func chromeTimeToUnix(chromeMicros int64) network.TimeSinceEpoch {
+	if chromeMicros == 0 {
+		return network.TimeSinceEpoch(0)
+	}
+	t := chromeEpoch.Add(time.Duration(chromeMicros) * time.Microsecond)
+	return network.TimeSinceEpoch(float64(t.UnixNano()) / float64(time.Second))
+}
+
+// chromeSameSite maps Chrome's cookies.samesite integer to the CDP enum.
+func chromeSameSite(v int) network.CookieSameSite {
+	switch v {
+	case 1:
+		return network.CookieSameSiteLax
+	case 2:
+		return network.CookieSameSiteStrict
+	default:
+		return network.CookieSameSiteNone
+	}
+}
+
+// chromePriority maps Chrome's cookies.priority integer to the CDP enum.
+func chromePriority(v int) network.CookiePriority {
+	switch v {
+	case 0:
+		return network.CookiePriorityLow
+	case 2:
+		return network.CookiePriorityHigh
+	default:
+		return network.CookiePriorityMedium
+	}
+}
@@ -0,0 +1,80 @@
+// Package cookiestore imports and exports Amazon session cookies in the
+// shapes produced by real browsers and browser-data extraction tools, and
+// injects them into a chromedp session via the CDP Network domain.
+package cookiestore
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// EncodeJSON marshals cookies to the JSON form used for both on-disk files
+// and storage.Driver-backed persistence.
+func EncodeJSON(cookies []*network.CookieParam) ([]byte, error) {
+	return json.MarshalIndent(cookies, "", "  ")
+}
+
+// DecodeJSON unmarshals cookies previously produced by EncodeJSON.
+func DecodeJSON(data []byte) ([]*network.CookieParam, error) {
+	var cookies []*network.CookieParam
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// ExportJSON writes cookies to filePath as CDP network.CookieParam JSON.
+func ExportJSON(filePath string, cookies []*network.CookieParam) error {
+	data, err := EncodeJSON(cookies)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0644)
+}
+
+// ImportJSON reads cookies previously written by ExportJSON.
+func ImportJSON(filePath string) ([]*network.CookieParam, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeJSON(data)
+}
+
+// SetCookies returns a chromedp action that injects cookies into the current
+// browser context via network.SetCookies, preserving fields such as
+// SameSite and Priority that chromedp.SetCookie's positional-argument form
+// drops.
+func SetCookies(cookies []*network.CookieParam) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		return network.SetCookies(cookies).Do(ctx)
+	})
+}
+
+// Validate navigates to url and reports whether readySelector is present in
+// the DOM afterwards, i.e. whether the injected cookies produced a logged-in
+// session.
+func Validate(ctx context.Context, url, readySelector string) (bool, error) {
+	var exists bool
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(url),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			return chromedp.Evaluate(
+				`document.querySelector(`+jsString(readySelector)+`) !== null`,
+				&exists,
+			).Do(ctx)
+		}),
+	)
+	return exists, err
+}
+
+// jsString renders s as a double-quoted JavaScript string literal for
+// inlining into an Evaluate expression.
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
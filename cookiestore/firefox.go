@@ -0,0 +1,62 @@
+package cookiestore
+
+import (
+	"database/sql"
+
+	"github.com/chromedp/cdproto/network"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ImportFirefox reads cookies from a Firefox profile's cookies.sqlite file
+// (the moz_cookies table).
+func ImportFirefox(path string) ([]*network.CookieParam, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT host, name, value, path, expiry, isSecure, isHttpOnly, sameSite FROM moz_cookies`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cookies []*network.CookieParam
+	for rows.Next() {
+		var (
+			host, name, value, path string
+			expiry                  int64
+			isSecure, isHTTPOnly    int
+			sameSite                int
+		)
+		if err := rows.Scan(&host, &name, &value, &path, &expiry, &isSecure, &isHTTPOnly, &sameSite); err != nil {
+			return nil, err
+		}
+		cookies = append(cookies, &network.CookieParam{
+			Name:     name,
+			Value:    value,
+			Domain:   host,
+			Path:     path,
+			Secure:   isSecure != 0,
+			HTTPOnly: isHTTPOnly != 0,
+			SameSite: firefoxSameSite(sameSite),
+			Priority: network.CookiePriorityMedium,
+			Expires:  network.TimeSinceEpoch(expiry),
+		})
+	}
+	return cookies, rows.Err()
+}
+
+// firefoxSameSite maps moz_cookies.sameSite (0=none, 1=lax, 2=strict) to the
+// CDP enum.
+func firefoxSameSite(v int) network.CookieSameSite {
+	switch v {
+	case 1:
+		return network.CookieSameSiteLax
+	case 2:
+		return network.CookieSameSiteStrict
+	default:
+		return network.CookieSameSiteNone
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/itmanvn/amazon-login/cookiestore"
+	"github.com/itmanvn/amazon-login/storage"
+)
+
+// WatchEntry is one line of the daemon's watch-list: an account to keep
+// logged in, and how often to re-validate its session.
+type WatchEntry struct {
+	Email    string
+	Interval time.Duration
+}
+
+// AccountStatus is the last known state of one watched account, reported by
+// the admin API's GET /accounts endpoint.
+type AccountStatus struct {
+	Email       string    `json:"email"`
+	LastChecked time.Time `json:"last_checked"`
+	LastRefresh time.Time `json:"last_refresh,omitempty"`
+	Valid       bool      `json:"valid"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Daemon keeps a watch-list of accounts' sessions warm, re-validating (and
+// re-logging-in, when needed) each on its own ticker via the underlying
+// Runner, and serves their status and cookies over an HTTP admin API.
+type Daemon struct {
+	Runner   *Runner
+	Storage  storage.Driver
+	Accounts map[string]Account
+
+	mu     sync.RWMutex
+	status map[string]AccountStatus
+}
+
+// NewDaemon builds a Daemon over runner, with accounts (keyed by email)
+// available to Watch and RefreshNow.
+func NewDaemon(runner *Runner, store storage.Driver, accounts []Account) *Daemon {
+	byEmail := make(map[string]Account, len(accounts))
+	for _, a := range accounts {
+		byEmail[a.Email] = a
+	}
+	return &Daemon{
+		Runner:   runner,
+		Storage:  store,
+		Accounts: byEmail,
+		status:   make(map[string]AccountStatus),
+	}
+}
+
+// Watch starts one refresh ticker per watchList entry and blocks until ctx
+// is done.
+func (d *Daemon) Watch(ctx context.Context, watchList []WatchEntry) {
+	var wg sync.WaitGroup
+	for _, entry := range watchList {
+		account, ok := d.Accounts[entry.Email]
+		if !ok {
+			log.Printf("Watch list entry %s has no matching account, skipping", entry.Email)
+			continue
+		}
+		interval := entry.Interval
+
+		wg.Add(1)
+		go func(account Account, interval time.Duration) {
+			defer wg.Done()
+			d.watchOne(ctx, account, interval)
+		}(account, interval)
+	}
+	<-ctx.Done()
+	wg.Wait()
+}
+
+func (d *Daemon) watchOne(ctx context.Context, account Account, interval time.Duration) {
+	d.refresh(ctx, account)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.refresh(ctx, account)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh re-validates (and, if invalid, re-logs-in) account's session via
+// the Runner, persists any refreshed cookies, and records the outcome for
+// the admin API and /metrics.
+func (d *Daemon) refresh(ctx context.Context, account Account) {
+	start := time.Now()
+	proxy := d.Runner.pickProxy(account)
+	cookies, err := d.Runner.loginAccount(ctx, account, proxy)
+	recordLoginOutcome(d.Runner.Metrics, err)
+
+	if err != nil {
+		d.Runner.Health.RecordFailure(proxy)
+		d.setStatus(AccountStatus{Email: account.Email, LastChecked: start, Valid: false, LastError: err.Error()})
+		log.Printf("Refresh failed for %s: %v", account.Email, err)
+		return
+	}
+
+	d.Runner.Health.RecordSuccess(proxy)
+	if data, err := cookiestore.EncodeJSON(cookies); err != nil {
+		log.Printf("Error encoding refreshed cookies for %s: %v", account.Email, err)
+	} else if err := d.Storage.PutCookies(account.Email, data); err != nil {
+		log.Printf("Error saving refreshed cookies for %s: %v", account.Email, err)
+	}
+	d.setStatus(AccountStatus{Email: account.Email, LastChecked: start, LastRefresh: start, Valid: true})
+	log.Printf("Refreshed session for %s", account.Email)
+}
+
+func (d *Daemon) setStatus(s AccountStatus) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status[s.Email] = s
+}
+
+// Status returns the last known status for every account refreshed so far,
+// sorted by email.
+func (d *Daemon) Status() []AccountStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	out := make([]AccountStatus, 0, len(d.status))
+	for _, s := range d.status {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Email < out[j].Email })
+	return out
+}
+
+// RefreshNow triggers an immediate refresh of email, used by the admin
+// API's POST /accounts/{email}/refresh. It returns an error if email has no
+// matching account; being on the watch list is not required.
+func (d *Daemon) RefreshNow(ctx context.Context, email string) error {
+	account, ok := d.Accounts[email]
+	if !ok {
+		return fmt.Errorf("daemon: unknown account %s", email)
+	}
+	d.refresh(ctx, account)
+	return nil
+}
@@ -0,0 +1,13 @@
+package main
+
+// Blank-import every storage driver so its factory registers itself; which
+// one actually runs is chosen at runtime via the config's storage.driver
+// setting. Drop an import here to build a smaller binary that only compiles
+// in the drivers you use.
+import (
+	_ "github.com/itmanvn/amazon-login/storage/azure"
+	_ "github.com/itmanvn/amazon-login/storage/filesystem"
+	_ "github.com/itmanvn/amazon-login/storage/gcs"
+	_ "github.com/itmanvn/amazon-login/storage/s3"
+	_ "github.com/itmanvn/amazon-login/storage/sql"
+)
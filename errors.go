@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/itmanvn/amazon-login/metrics"
+)
+
+// Typed errors surfaced by performLogin when Amazon presents something other
+// than a normal sign-in flow. Runner.loginAccount treats these as terminal:
+// they need a solver, an OTP provider, or a different proxy, not a retry.
+var (
+	// ErrCaptcha means a captcha challenge was shown and no captcha.Solver
+	// was configured to answer it.
+	ErrCaptcha = errors.New("amazon-login: captcha challenge")
+
+	// ErrOTP means an MFA/OTP challenge was shown and no otp.Provider was
+	// configured to answer it.
+	ErrOTP = errors.New("amazon-login: otp challenge")
+
+	// ErrLocked means the account is locked, or Amazon interrupted the
+	// flow with an account-status message that requires manual review.
+	ErrLocked = errors.New("amazon-login: account locked")
+
+	// ErrProxyBlocked means the sign-in page itself never loaded, which
+	// usually means the proxy's IP has been rate-limited or blocked.
+	ErrProxyBlocked = errors.New("amazon-login: proxy blocked")
+)
+
+// recordLoginOutcome buckets a login attempt's result (nil on success) into
+// m's counters, used by both the one-shot worker pool and the daemon's
+// refresh loop so /metrics reflects either.
+func recordLoginOutcome(m *metrics.Counters, err error) {
+	m.IncLoginAttempts()
+	if err == nil {
+		m.IncLoginSuccess()
+		return
+	}
+	m.IncLoginFailure()
+	switch {
+	case errors.Is(err, ErrCaptcha):
+		m.IncCaptchaHits()
+	case errors.Is(err, ErrOTP):
+		m.IncOTPHits()
+	case errors.Is(err, ErrProxyBlocked):
+		m.IncProxyFailures()
+	}
+}
@@ -0,0 +1,55 @@
+// Package metrics tracks the small set of operational counters the daemon's
+// admin API exposes (login attempts, captcha/OTP hits, proxy failures), and
+// renders them in Prometheus's text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Counters is safe for concurrent use by multiple goroutines.
+type Counters struct {
+	loginAttempts int64
+	loginSuccess  int64
+	loginFailure  int64
+	captchaHits   int64
+	otpHits       int64
+	proxyFailures int64
+}
+
+// NewCounters returns a Counters with every count at zero.
+func NewCounters() *Counters {
+	return &Counters{}
+}
+
+func (c *Counters) IncLoginAttempts() { atomic.AddInt64(&c.loginAttempts, 1) }
+func (c *Counters) IncLoginSuccess()  { atomic.AddInt64(&c.loginSuccess, 1) }
+func (c *Counters) IncLoginFailure()  { atomic.AddInt64(&c.loginFailure, 1) }
+func (c *Counters) IncCaptchaHits()   { atomic.AddInt64(&c.captchaHits, 1) }
+func (c *Counters) IncOTPHits()       { atomic.AddInt64(&c.otpHits, 1) }
+func (c *Counters) IncProxyFailures() { atomic.AddInt64(&c.proxyFailures, 1) }
+
+// WriteText renders every counter to w in Prometheus's plain text
+// exposition format.
+func (c *Counters) WriteText(w io.Writer) error {
+	samples := []struct {
+		name  string
+		help  string
+		value int64
+	}{
+		{"amzlogin_login_attempts_total", "Total login attempts.", atomic.LoadInt64(&c.loginAttempts)},
+		{"amzlogin_login_success_total", "Total successful logins.", atomic.LoadInt64(&c.loginSuccess)},
+		{"amzlogin_login_failure_total", "Total failed logins.", atomic.LoadInt64(&c.loginFailure)},
+		{"amzlogin_captcha_hits_total", "Total captcha challenges encountered.", atomic.LoadInt64(&c.captchaHits)},
+		{"amzlogin_otp_hits_total", "Total OTP challenges encountered.", atomic.LoadInt64(&c.otpHits)},
+		{"amzlogin_proxy_failures_total", "Total proxy failures recorded.", atomic.LoadInt64(&c.proxyFailures)},
+	}
+	for _, s := range samples {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", s.name, s.help, s.name, s.name, s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CallbackProvider fetches the current OTP code from an external HTTP
+// endpoint, for setups where the authenticator lives outside this process
+// (e.g. a shared inbox poller or a teammate's phone).
+type CallbackProvider struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (p *CallbackProvider) httpClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// Code implements Provider. It issues a GET request to URL with the account
+// email attached as a query parameter and expects a JSON body of the form
+// {"code": "123456"}.
+func (p *CallbackProvider) Code(ctx context.Context, account string) (string, error) {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("account", account)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("otp: callback %s returned %s", p.URL, resp.Status)
+	}
+
+	var result struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if result.Code == "" {
+		return "", fmt.Errorf("otp: callback %s returned empty code", p.URL)
+	}
+	return result.Code, nil
+}
@@ -0,0 +1,11 @@
+// Package otp defines the interface used to complete Amazon's MFA/OTP
+// challenge, plus TOTP and HTTP-callback implementations.
+package otp
+
+import "context"
+
+// Provider produces the one-time code to submit for account's MFA
+// challenge.
+type Provider interface {
+	Code(ctx context.Context, account string) (string, error)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProxyStats tracks recent success/failure counts for a single proxy.
+type ProxyStats struct {
+	Successes   int       `json:"successes"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure,omitempty"`
+}
+
+// FailureRate returns the fraction of observed attempts that failed, in [0, 1].
+func (s *ProxyStats) FailureRate() float64 {
+	total := s.Successes + s.Failures
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(total)
+}
+
+// ProxyHealth is a mutex-protected map of proxy health signal, keyed by
+// "server:port". It can be persisted to disk so that health learned in one
+// run informs proxy selection in the next.
+type ProxyHealth struct {
+	mu    sync.Mutex
+	stats map[string]*ProxyStats
+}
+
+func proxyKey(p Proxy) string {
+	return fmt.Sprintf("%s:%s", p.Server, p.Port)
+}
+
+// NewProxyHealth returns an empty ProxyHealth tracker.
+func NewProxyHealth() *ProxyHealth {
+	return &ProxyHealth{stats: make(map[string]*ProxyStats)}
+}
+
+// LoadProxyHealth reads previously persisted health state from filePath. A
+// missing file is not an error; it yields an empty tracker.
+func LoadProxyHealth(filePath string) (*ProxyHealth, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return NewProxyHealth(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]*ProxyStats)
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+	return &ProxyHealth{stats: stats}, nil
+}
+
+// Save persists the current health state to filePath.
+func (h *ProxyHealth) Save(filePath string) error {
+	h.mu.Lock()
+	data, err := json.MarshalIndent(h.stats, "", "  ")
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0644)
+}
+
+// RecordSuccess increments the success counter for p.
+func (h *ProxyHealth) RecordSuccess(p Proxy) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.statFor(p)
+	s.Successes++
+}
+
+// RecordFailure increments the failure counter for p and stamps the failure time.
+func (h *ProxyHealth) RecordFailure(p Proxy) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.statFor(p)
+	s.Failures++
+	s.LastFailure = time.Now()
+}
+
+// statFor returns the stats entry for p, creating it if necessary. Callers
+// must hold h.mu.
+func (h *ProxyHealth) statFor(p Proxy) *ProxyStats {
+	key := proxyKey(p)
+	s, ok := h.stats[key]
+	if !ok {
+		s = &ProxyStats{}
+		h.stats[key] = s
+	}
+	return s
+}
+
+// IsHealthy reports whether p's recent failure rate is at or below
+// threshold. A proxy with no recorded attempts is considered healthy.
+func (h *ProxyHealth) IsHealthy(p Proxy, threshold float64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.stats[proxyKey(p)]
+	if !ok {
+		return true
+	}
+	return s.FailureRate() <= threshold
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// withRetry calls fn up to attempts times (at least once), doubling interval
+// between each attempt. It gives up immediately, without retrying, when fn
+// returns one of the typed challenge errors: a captcha, OTP, lock, or proxy
+// block needs a different remedy than trying the same thing again.
+func withRetry(ctx context.Context, attempts int, interval time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		err = fn()
+		if err == nil || isTerminal(err) {
+			return err
+		}
+		if attempt == attempts-1 {
+			break
+		}
+
+		wait := interval * time.Duration(uint(1)<<uint(attempt))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// isTerminal reports whether err is one of the typed challenge errors that
+// withRetry should not paper over by trying again.
+func isTerminal(err error) bool {
+	return errors.Is(err, ErrCaptcha) ||
+		errors.Is(err, ErrOTP) ||
+		errors.Is(err, ErrLocked) ||
+		errors.Is(err, ErrProxyBlocked)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/itmanvn/amazon-login/captcha"
+	"github.com/itmanvn/amazon-login/config"
+	"github.com/itmanvn/amazon-login/cookiestore"
+	"github.com/itmanvn/amazon-login/metrics"
+	"github.com/itmanvn/amazon-login/otp"
+	"github.com/itmanvn/amazon-login/storage"
+)
+
+// maxProxyFailureRate is the failure rate above which a proxy is skipped by
+// the scoring picker in favor of a fresh one from the pool.
+const maxProxyFailureRate = 0.5
+
+// LoginResult is reported on a Runner's results channel once a worker
+// finishes processing an account.
+type LoginResult struct {
+	Account Account
+	Proxy   Proxy
+	Cookies []*network.CookieParam
+	Err     error
+	Elapsed time.Duration
+}
+
+// Runner drives a pool of workers that log in to many accounts concurrently,
+// each behind its own isolated chromedp allocator and proxy.
+type Runner struct {
+	Config      *config.Config
+	Storage     storage.Driver
+	Proxies     []Proxy
+	Health      *ProxyHealth
+	Solver      captcha.Solver
+	OTPProvider otp.Provider
+	Metrics     *metrics.Counters
+
+	mu      sync.Mutex
+	Mapping map[string]Proxy
+}
+
+// NewRunner builds a Runner with the given config, storage driver, proxy
+// pool, account-to-proxy mapping, health tracker, and metrics counters. The
+// captcha.Solver and otp.Provider used for login challenges are constructed
+// from cfg.Captcha/cfg.OTP.
+func NewRunner(cfg *config.Config, store storage.Driver, proxies []Proxy, mapping map[string]Proxy, health *ProxyHealth, counters *metrics.Counters) (*Runner, error) {
+	solver, err := buildSolver(cfg.Captcha)
+	if err != nil {
+		return nil, err
+	}
+	otpProvider, err := buildOTPProvider(cfg.OTP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		Config:      cfg,
+		Storage:     store,
+		Proxies:     proxies,
+		Mapping:     mapping,
+		Health:      health,
+		Solver:      solver,
+		OTPProvider: otpProvider,
+		Metrics:     counters,
+	}, nil
+}
+
+// Run spawns Concurrency workers that pull accounts off an internal job
+// channel and log in, each through its own proxy and browser allocator. The
+// returned channel is closed once every account has been processed.
+func (r *Runner) Run(ctx context.Context, accounts []Account) <-chan LoginResult {
+	jobs := make(chan Account, len(accounts))
+	results := make(chan LoginResult, len(accounts))
+
+	concurrency := r.Config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go r.worker(ctx, jobs, results, &wg)
+	}
+
+	for _, account := range accounts {
+		jobs <- account
+	}
+	close(jobs)
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+func (r *Runner) worker(ctx context.Context, jobs <-chan Account, results chan<- LoginResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for account := range jobs {
+		start := time.Now()
+		proxy := r.pickProxy(account)
+		cookies, err := r.loginAccount(ctx, account, proxy)
+		recordLoginOutcome(r.Metrics, err)
+		if err != nil {
+			r.Health.RecordFailure(proxy)
+		} else {
+			r.Health.RecordSuccess(proxy)
+		}
+		results <- LoginResult{
+			Account: account,
+			Proxy:   proxy,
+			Cookies: cookies,
+			Err:     err,
+			Elapsed: time.Since(start),
+		}
+	}
+}
+
+// pickProxy returns the proxy to use for account, preferring its previously
+// mapped proxy if it is still healthy, and otherwise rotating in a fresh,
+// healthy proxy from the pool (or any pool proxy, if none is healthy).
+func (r *Runner) pickProxy(account Account) Proxy {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if proxy, ok := r.Mapping[account.Email]; ok && r.Health.IsHealthy(proxy, maxProxyFailureRate) {
+		return proxy
+	}
+
+	healthy := make([]Proxy, 0, len(r.Proxies))
+	for _, p := range r.Proxies {
+		if r.Health.IsHealthy(p, maxProxyFailureRate) {
+			healthy = append(healthy, p)
+		}
+	}
+	pool := r.Proxies
+	if len(healthy) > 0 {
+		pool = healthy
+	}
+
+	proxy := pool[rand.Intn(len(pool))]
+	r.Mapping[account.Email] = proxy
+	return proxy
+}
+
+// loginAccount drives a single isolated browser instance through cookie
+// reuse or fresh login for account, behind proxy. It tries any previously
+// imported or exported cookies first, validates them, and only falls back
+// to password login if they are missing or invalid.
+func (r *Runner) loginAccount(ctx context.Context, account Account, proxy Proxy) ([]*network.CookieParam, error) {
+	cfg := r.Config
+	allocOpts := []chromedp.ExecAllocatorOption{
+		chromedp.NoFirstRun,
+		chromedp.NoDefaultBrowserCheck,
+		chromedp.UserAgent(cfg.UserAgent),
+		chromedp.WindowSize(cfg.WindowWidth, cfg.WindowHeight),
+		chromedp.ProxyServer(fmt.Sprintf("http://%s:%s", proxy.Server, proxy.Port)),
+	}
+	if cfg.Headless {
+		allocOpts = append(allocOpts, chromedp.Headless)
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocOpts...)
+	defer cancelAlloc()
+
+	browserCtx, cancelCtx := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	defer cancelCtx()
+
+	cookiesChan := make(chan []*network.CookieParam, 1)
+
+	data, err := r.Storage.GetCookies(account.Email)
+	if err == nil {
+		cookies, err := cookiestore.DecodeJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Cookies found for %s, attempting to reuse", account.Email)
+		err = chromedp.Run(browserCtx, cookiestore.SetCookies(cookies))
+		if err != nil {
+			return nil, err
+		}
+		valid, err := cookiestore.Validate(browserCtx, cfg.Active.HomeURL, cfg.Active.Selectors.AccountList)
+		if err != nil {
+			return nil, err
+		}
+		if valid {
+			log.Printf("Cookies valid for %s", account.Email)
+			return cookies, nil
+		}
+		log.Printf("Cookies invalid for %s, performing fresh login", account.Email)
+		if err := r.freshLogin(browserCtx, account, cookiesChan); err != nil {
+			return nil, err
+		}
+		return <-cookiesChan, nil
+	}
+
+	log.Printf("No cookies found for %s, performing fresh login", account.Email)
+	if err := r.freshLogin(browserCtx, account, cookiesChan); err != nil {
+		return nil, err
+	}
+	return <-cookiesChan, nil
+}
+
+// freshLogin runs performLogin with retry/backoff per r.Config's
+// RetryCount/RetryInterval. Typed challenge errors (captcha, OTP, locked,
+// proxy-blocked) are not retried; see withRetry.
+func (r *Runner) freshLogin(ctx context.Context, account Account, cookiesChan chan<- []*network.CookieParam) error {
+	cfg := r.Config
+	return withRetry(ctx, cfg.RetryCount, cfg.RetryInterval, func() error {
+		return performLogin(ctx, account, cfg, r.Solver, r.OTPProvider, cookiesChan)
+	})
+}
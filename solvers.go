@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/itmanvn/amazon-login/captcha"
+	"github.com/itmanvn/amazon-login/config"
+	"github.com/itmanvn/amazon-login/otp"
+)
+
+// buildSolver constructs the captcha.Solver named by cfg.Provider. An empty
+// Provider disables automatic solving, returning a nil Solver; captchas then
+// surface as ErrCaptcha.
+func buildSolver(cfg config.CaptchaConfig) (captcha.Solver, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "2captcha", "anti-captcha":
+		return &captcha.TwoCaptchaSolver{APIKey: cfg.APIKey}, nil
+	default:
+		return nil, fmt.Errorf("amazon-login: unknown captcha provider %q", cfg.Provider)
+	}
+}
+
+// buildOTPProvider constructs the otp.Provider named by cfg.Provider. An
+// empty Provider disables automatic completion, returning a nil Provider;
+// OTP challenges then surface as ErrOTP.
+func buildOTPProvider(cfg config.OTPConfig) (otp.Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "totp":
+		return otp.TOTPProvider{Secret: cfg.Secret}, nil
+	case "callback":
+		return &otp.CallbackProvider{URL: cfg.CallbackURL}, nil
+	default:
+		return nil, fmt.Errorf("amazon-login: unknown otp provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,95 @@
+// Package azure is an Azure Blob Storage-backed storage.Driver.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	amzstorage "github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
+)
+
+const driverName = "azure"
+
+func init() {
+	factory.Register(driverName, &driverFactory{})
+}
+
+type driverFactory struct{}
+
+// Create builds an Azure Blob driver from parameters: "account_name",
+// "account_key", and "container" (all required).
+func (driverFactory) Create(parameters map[string]interface{}) (amzstorage.Driver, error) {
+	accountName, _ := parameters["account_name"].(string)
+	accountKey, _ := parameters["account_key"].(string)
+	container, _ := parameters["container"].(string)
+	if accountName == "" || accountKey == "" || container == "" {
+		return nil, fmt.Errorf("azure: \"account_name\", \"account_key\", and \"container\" parameters are required")
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	containerURL := azblob.NewContainerURL(
+		fmt.Sprintf("https://%s.blob.core.windows.net/%s", accountName, container),
+		pipeline,
+	)
+
+	return &Driver{containerURL: containerURL}, nil
+}
+
+// Driver is the Azure Blob-backed storage.Driver.
+type Driver struct {
+	containerURL interface {
+		NewBlockBlobURL(string) azblob.BlockBlobURL
+	}
+}
+
+// GetCookies implements storage.Driver.
+func (d *Driver) GetCookies(email string) ([]byte, error) {
+	return d.get("cookies/" + strings.Replace(email, "@", "_", -1) + ".json")
+}
+
+// PutCookies implements storage.Driver.
+func (d *Driver) PutCookies(email string, data []byte) error {
+	return d.put("cookies/"+strings.Replace(email, "@", "_", -1)+".json", data)
+}
+
+// GetMapping implements storage.Driver.
+func (d *Driver) GetMapping() ([]byte, error) {
+	return d.get("account_proxy_mapping.json")
+}
+
+// PutMapping implements storage.Driver.
+func (d *Driver) PutMapping(data []byte) error {
+	return d.put("account_proxy_mapping.json", data)
+}
+
+func (d *Driver) get(name string) ([]byte, error) {
+	ctx := context.Background()
+	blobURL := d.containerURL.NewBlockBlobURL(name)
+	resp, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		if stgErr, ok := err.(azblob.StorageError); ok && stgErr.ServiceCode() == azblob.ServiceCodeBlobNotFound {
+			return nil, amzstorage.ErrNotFound
+		}
+		return nil, err
+	}
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+func (d *Driver) put(name string, data []byte) error {
+	ctx := context.Background()
+	blobURL := d.containerURL.NewBlockBlobURL(name)
+	_, err := blobURL.Upload(ctx, bytes.NewReader(data), azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{})
+	return err
+}
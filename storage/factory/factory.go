@@ -0,0 +1,41 @@
+// Package factory is a driver registry for the storage package, modeled on
+// the same pattern used by docker/distribution's registry storage drivers:
+// each concrete driver registers a Factory under a name in its init(), and
+// callers select a driver by name at runtime without needing to import it
+// directly.
+package factory
+
+import (
+	"fmt"
+
+	"github.com/itmanvn/amazon-login/storage"
+)
+
+// Factory creates a storage.Driver from driver-specific parameters.
+type Factory interface {
+	Create(parameters map[string]interface{}) (storage.Driver, error)
+}
+
+var driverFactories = make(map[string]Factory)
+
+// Register makes a Factory available under name. It is meant to be called
+// from a driver package's init() function; it panics on duplicate
+// registration since that indicates two drivers compiled in under the same
+// name.
+func Register(name string, f Factory) {
+	if _, exists := driverFactories[name]; exists {
+		panic(fmt.Sprintf("storage: factory already registered for %q", name))
+	}
+	driverFactories[name] = f
+}
+
+// Create builds a storage.Driver for the named, registered factory. Driver
+// packages are selected at compile time via blank import; Create returns an
+// error if the named driver wasn't compiled in.
+func Create(name string, parameters map[string]interface{}) (storage.Driver, error) {
+	f, ok := driverFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("storage: no driver registered for %q (is it blank-imported?)", name)
+	}
+	return f.Create(parameters)
+}
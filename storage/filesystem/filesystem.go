@@ -0,0 +1,74 @@
+// Package filesystem is the local-disk storage.Driver, preserving the
+// tool's original behavior of one cookies_<email>.json file per account and
+// a single mapping JSON file, rooted under a configurable directory.
+package filesystem
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
+)
+
+const driverName = "filesystem"
+
+func init() {
+	factory.Register(driverName, &driverFactory{})
+}
+
+type driverFactory struct{}
+
+// Create builds a filesystem driver rooted at parameters["rootdirectory"],
+// defaulting to the current working directory.
+func (driverFactory) Create(parameters map[string]interface{}) (storage.Driver, error) {
+	root, _ := parameters["rootdirectory"].(string)
+	if root == "" {
+		root = "."
+	}
+	return &Driver{root: root}, nil
+}
+
+// Driver is the filesystem-backed storage.Driver.
+type Driver struct {
+	root string
+}
+
+func (d *Driver) cookiesPath(email string) string {
+	return filepath.Join(d.root, fmt.Sprintf("cookies_%s.json", strings.Replace(email, "@", "_", -1)))
+}
+
+func (d *Driver) mappingPath() string {
+	return filepath.Join(d.root, "account_proxy_mapping.json")
+}
+
+// GetCookies implements storage.Driver.
+func (d *Driver) GetCookies(email string) ([]byte, error) {
+	return readFile(d.cookiesPath(email))
+}
+
+// PutCookies implements storage.Driver.
+func (d *Driver) PutCookies(email string, data []byte) error {
+	return ioutil.WriteFile(d.cookiesPath(email), data, 0644)
+}
+
+// GetMapping implements storage.Driver.
+func (d *Driver) GetMapping() ([]byte, error) {
+	return readFile(d.mappingPath())
+}
+
+// PutMapping implements storage.Driver.
+func (d *Driver) PutMapping(data []byte) error {
+	return ioutil.WriteFile(d.mappingPath(), data, 0644)
+}
+
+func readFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, storage.ErrNotFound
+	}
+	return data, err
+}
@@ -0,0 +1,94 @@
+// Package gcs is a Google Cloud Storage-backed storage.Driver.
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	amzstorage "github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
+)
+
+const driverName = "gcs"
+
+func init() {
+	factory.Register(driverName, &driverFactory{})
+}
+
+type driverFactory struct{}
+
+// Create builds a GCS driver from parameters: "bucket" (required) and
+// optional "credentials_file" for a service-account JSON key; when omitted,
+// application-default credentials are used.
+func (driverFactory) Create(parameters map[string]interface{}) (amzstorage.Driver, error) {
+	bucket, _ := parameters["bucket"].(string)
+	if bucket == "" {
+		return nil, fmt.Errorf("gcs: \"bucket\" parameter is required")
+	}
+
+	var opts []option.ClientOption
+	if credFile, _ := parameters["credentials_file"].(string); credFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credFile))
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Driver{bucket: client.Bucket(bucket)}, nil
+}
+
+// Driver is the GCS-backed storage.Driver.
+type Driver struct {
+	bucket *storage.BucketHandle
+}
+
+// GetCookies implements storage.Driver.
+func (d *Driver) GetCookies(email string) ([]byte, error) {
+	return d.get("cookies/" + strings.Replace(email, "@", "_", -1) + ".json")
+}
+
+// PutCookies implements storage.Driver.
+func (d *Driver) PutCookies(email string, data []byte) error {
+	return d.put("cookies/"+strings.Replace(email, "@", "_", -1)+".json", data)
+}
+
+// GetMapping implements storage.Driver.
+func (d *Driver) GetMapping() ([]byte, error) {
+	return d.get("account_proxy_mapping.json")
+}
+
+// PutMapping implements storage.Driver.
+func (d *Driver) PutMapping(data []byte) error {
+	return d.put("account_proxy_mapping.json", data)
+}
+
+func (d *Driver) get(name string) ([]byte, error) {
+	ctx := context.Background()
+	r, err := d.bucket.Object(name).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, amzstorage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+func (d *Driver) put(name string, data []byte) error {
+	ctx := context.Background()
+	w := d.bucket.Object(name).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
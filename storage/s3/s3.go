@@ -0,0 +1,107 @@
+// Package s3 is an Amazon S3-backed storage.Driver.
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	amzstorage "github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
+)
+
+const driverName = "s3"
+
+func init() {
+	factory.Register(driverName, &driverFactory{})
+}
+
+type driverFactory struct{}
+
+// Create builds an S3 driver from parameters: "bucket" (required), "region"
+// (required), and optional "prefix" for namespacing keys within the bucket.
+func (driverFactory) Create(parameters map[string]interface{}) (amzstorage.Driver, error) {
+	bucket, _ := parameters["bucket"].(string)
+	region, _ := parameters["region"].(string)
+	if bucket == "" || region == "" {
+		return nil, fmt.Errorf("s3: both \"bucket\" and \"region\" parameters are required")
+	}
+	prefix, _ := parameters["prefix"].(string)
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Driver{
+		bucket:     bucket,
+		prefix:     prefix,
+		client:     s3.New(sess),
+		uploader:   s3manager.NewUploader(sess),
+		downloader: s3manager.NewDownloader(sess),
+	}, nil
+}
+
+// Driver is the S3-backed storage.Driver.
+type Driver struct {
+	bucket     string
+	prefix     string
+	client     *s3.S3
+	uploader   *s3manager.Uploader
+	downloader *s3manager.Downloader
+}
+
+func (d *Driver) key(parts ...string) string {
+	return path.Join(append([]string{d.prefix}, parts...)...)
+}
+
+// GetCookies implements storage.Driver.
+func (d *Driver) GetCookies(email string) ([]byte, error) {
+	return d.get(d.key("cookies", strings.Replace(email, "@", "_", -1) + ".json"))
+}
+
+// PutCookies implements storage.Driver.
+func (d *Driver) PutCookies(email string, data []byte) error {
+	return d.put(d.key("cookies", strings.Replace(email, "@", "_", -1)+".json"), data)
+}
+
+// GetMapping implements storage.Driver.
+func (d *Driver) GetMapping() ([]byte, error) {
+	return d.get(d.key("account_proxy_mapping.json"))
+}
+
+// PutMapping implements storage.Driver.
+func (d *Driver) PutMapping(data []byte) error {
+	return d.put(d.key("account_proxy_mapping.json"), data)
+}
+
+func (d *Driver) get(key string) ([]byte, error) {
+	buf := aws.NewWriteAtBuffer(nil)
+	_, err := d.downloader.Download(buf, &s3.GetObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, amzstorage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Driver) put(key string, data []byte) error {
+	_, err := d.uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
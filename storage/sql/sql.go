@@ -0,0 +1,107 @@
+// Package sql is a database/sql-backed storage.Driver, supporting both
+// SQLite and Postgres through the standard driver registry.
+package sql
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	amzstorage "github.com/itmanvn/amazon-login/storage"
+	"github.com/itmanvn/amazon-login/storage/factory"
+)
+
+const driverName = "sql"
+
+func init() {
+	factory.Register(driverName, &driverFactory{})
+}
+
+type driverFactory struct{}
+
+// Create builds a SQL driver from parameters: "driver" (one of "sqlite3" or
+// "postgres") and "dsn" (both required).
+func (driverFactory) Create(parameters map[string]interface{}) (amzstorage.Driver, error) {
+	sqlDriver, _ := parameters["driver"].(string)
+	dsn, _ := parameters["dsn"].(string)
+	if sqlDriver == "" || dsn == "" {
+		return nil, fmt.Errorf("sql: \"driver\" and \"dsn\" parameters are required")
+	}
+
+	db, err := sql.Open(sqlDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+
+	return &Driver{db: db}, nil
+}
+
+// migrate creates the tables used to hold cookies and the mapping, if they
+// don't already exist.
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS cookies (email TEXT PRIMARY KEY, data TEXT NOT NULL)`)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS proxy_mapping (id INTEGER PRIMARY KEY CHECK (id = 1), data TEXT NOT NULL)`)
+	return err
+}
+
+// Driver is the database/sql-backed storage.Driver.
+type Driver struct {
+	db *sql.DB
+}
+
+// GetCookies implements storage.Driver.
+func (d *Driver) GetCookies(email string) ([]byte, error) {
+	var data string
+	err := d.db.QueryRow(`SELECT data FROM cookies WHERE email = $1`, email).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, amzstorage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// PutCookies implements storage.Driver.
+func (d *Driver) PutCookies(email string, data []byte) error {
+	_, err := d.db.Exec(
+		`INSERT INTO cookies (email, data) VALUES ($1, $2)
+		 ON CONFLICT (email) DO UPDATE SET data = excluded.data`,
+		email, string(data),
+	)
+	return err
+}
+
+// GetMapping implements storage.Driver.
+func (d *Driver) GetMapping() ([]byte, error) {
+	var data string
+	err := d.db.QueryRow(`SELECT data FROM proxy_mapping WHERE id = 1`).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, amzstorage.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// PutMapping implements storage.Driver.
+func (d *Driver) PutMapping(data []byte) error {
+	_, err := d.db.Exec(
+		`INSERT INTO proxy_mapping (id, data) VALUES (1, $1)
+		 ON CONFLICT (id) DO UPDATE SET data = excluded.data`,
+		string(data),
+	)
+	return err
+}
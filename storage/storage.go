@@ -0,0 +1,28 @@
+// Package storage defines the persistence interface for session cookies and
+// the account-to-proxy mapping, so that a fleet of runners can share state
+// without a shared filesystem. Concrete drivers live in subpackages and
+// register themselves with the storage/factory package via a blank import.
+package storage
+
+import "errors"
+
+// ErrNotFound is returned by Driver methods when the requested cookies or
+// mapping have never been stored.
+var ErrNotFound = errors.New("storage: not found")
+
+// Driver persists and retrieves the raw JSON bytes for an account's cookies
+// and for the account-to-proxy mapping. Implementations are free to encode
+// these however they like internally, as long as PutCookies followed by
+// GetCookies round-trips the same bytes.
+type Driver interface {
+	// GetCookies returns the stored cookie JSON for email, or ErrNotFound
+	// if none has been stored yet.
+	GetCookies(email string) ([]byte, error)
+	// PutCookies stores the cookie JSON for email.
+	PutCookies(email string, data []byte) error
+	// GetMapping returns the stored account-to-proxy mapping JSON, or
+	// ErrNotFound if none has been stored yet.
+	GetMapping() ([]byte, error)
+	// PutMapping stores the account-to-proxy mapping JSON.
+	PutMapping(data []byte) error
+}